@@ -0,0 +1,125 @@
+// Command analyze sends local images and videos to a vision model and
+// prints back a description, or runs as an OpenAI-compatible server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	openaibackend "github.com/DTerian64/David64_Award_Nominations/pkg/backend/openai"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/cache"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/media"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+	runAnalyze(os.Args[1:])
+}
+
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "number of inputs to analyze in parallel")
+	format := fs.String("format", "text", "output format: text or json")
+	stream := fs.Bool("stream", false, "print image descriptions token by token as they arrive (images only)")
+	useCache := fs.Bool("cache", true, "reuse cached results for images that perceptually match a prior analysis")
+	noCache := fs.Bool("no-cache", false, "disable the cache (shorthand for -cache=false)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: analyze [flags] <path|url|dir|glob> ...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	inputArgs := fs.Args()
+	if len(inputArgs) < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("Error: unsupported -format %q (want text or json)\n", *format)
+		os.Exit(1)
+	}
+	if *stream && *format == "json" {
+		fmt.Println("Error: -stream cannot be combined with -format=json")
+		os.Exit(1)
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		fmt.Println("Error: OPENAI_API_KEY environment variable not set")
+		os.Exit(1)
+	}
+
+	inputs, cleanup, err := media.ResolveInputs(inputArgs)
+	if err != nil {
+		// os.Exit skips deferred calls, so clean up explicitly before it
+		// rather than deferring from here on.
+		cleanup()
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(inputs) == 0 {
+		cleanup()
+		fmt.Println("Error: no matching inputs found")
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	b := openaibackend.New(apiKey)
+	ctx := context.Background()
+
+	if *stream {
+		runStream(ctx, b, inputs)
+		return
+	}
+
+	var imgCache *cache.Cache
+	if *useCache && !*noCache {
+		imgCache = openImageCache()
+	}
+
+	results := media.AnalyzeAll(ctx, b, inputs, *concurrency, imgCache)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, result := range results {
+			_ = encoder.Encode(result)
+		}
+		return
+	}
+
+	for _, result := range results {
+		fmt.Printf("\n--- %s ---\n", result.Source)
+		if result.Error != "" {
+			fmt.Printf("Error: %s\n", result.Error)
+			continue
+		}
+		if result.Cached {
+			fmt.Print("(cached) ")
+		}
+		fmt.Println(result.Description)
+	}
+}
+
+// openImageCache opens the on-disk perceptual-hash cache, logging and
+// disabling caching for this run if it can't be opened rather than
+// failing the whole analysis.
+func openImageCache() *cache.Cache {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cache disabled: %v\n", err)
+		return nil
+	}
+	c, err := cache.Open(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cache disabled: %v\n", err)
+		return nil
+	}
+	return c
+}
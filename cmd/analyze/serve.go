@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+	openaibackend "github.com/DTerian64/David64_Award_Nominations/pkg/backend/openai"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/server"
+)
+
+// runServe starts the LocalAI-compatible HTTP server exposing
+// /v1/chat/completions and /v1/images/generations.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: analyze serve [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		fmt.Println("Error: OPENAI_API_KEY environment variable not set")
+		os.Exit(1)
+	}
+
+	registry := backend.NewRegistry()
+	registry.Register("openai", openaibackend.New(apiKey))
+	// A "local" backend (llama.cpp / stable-diffusion) can be registered
+	// here once one exists.
+
+	srv := server.New(registry)
+	fmt.Printf("Listening on %s\n", *addr)
+	if err := srv.ListenAndServe(*addr); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
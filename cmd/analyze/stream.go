@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/media"
+)
+
+// runStream analyzes inputs sequentially, printing tokens to stdout as
+// they arrive. Video inputs fall back to a non-streaming analysis since
+// only image streaming is supported.
+func runStream(ctx context.Context, b backend.Backend, inputs []media.Input) {
+	for _, input := range inputs {
+		fmt.Printf("\n--- %s ---\n", input.Source)
+
+		if media.IsVideo(input.Path) {
+			description, _, err := media.Analyze(ctx, b, input.Path, nil)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			fmt.Println(description)
+			continue
+		}
+
+		err := media.AnalyzeImageStream(ctx, b, input.Path, func(token string) error {
+			fmt.Print(token)
+			return nil
+		})
+		fmt.Println()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+}
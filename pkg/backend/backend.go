@@ -0,0 +1,63 @@
+// Package backend defines the interface that chat/image-generation
+// providers implement, plus a registry so the server can pick one by name.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/schema"
+)
+
+// Backend is something that can serve chat completions and image
+// generations, e.g. a proxy to api.openai.com or a local model runtime.
+type Backend interface {
+	Chat(ctx context.Context, req *schema.OpenAIRequest) (*schema.OpenAIResponse, error)
+	GenerateImage(ctx context.Context, req *schema.ImageGenerationRequest) (*schema.ImageGenerationResponse, error)
+}
+
+// RateLimitInfo summarizes a provider's notion of how many requests and
+// tokens a caller has left before it starts rejecting them, and how long
+// until those quotas reset. Backends that can report this implement a
+// ChatWithRateLimit method alongside Chat; see pkg/media's rateLimitChatter
+// for how batch callers use it to pace themselves.
+type RateLimitInfo struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Duration
+	LimitTokens       int
+	RemainingTokens   int
+	ResetTokens       time.Duration
+}
+
+// Registry is a name-keyed collection of backends, used by the server to
+// route requests to the configured provider.
+type Registry struct {
+	mu       sync.RWMutex
+	backends map[string]Backend
+}
+
+// NewRegistry returns an empty Registry ready for use.
+func NewRegistry() *Registry {
+	return &Registry{backends: make(map[string]Backend)}
+}
+
+// Register adds backend b under name, overwriting any existing entry.
+func (r *Registry) Register(name string, b Backend) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.backends[name] = b
+}
+
+// Get returns the backend registered under name.
+func (r *Registry) Get(name string) (Backend, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("no backend registered for %q", name)
+	}
+	return b, nil
+}
@@ -0,0 +1,236 @@
+// Package openai implements pkg/backend.Backend by proxying requests to
+// api.openai.com.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/schema"
+)
+
+const baseURL = "https://api.openai.com/v1"
+
+// Backend proxies chat completions and image generations to OpenAI.
+type Backend struct {
+	apiKey     string
+	httpClient *http.Client
+	maxRetries int
+}
+
+// New returns a Backend authenticated with apiKey.
+func New(apiKey string) *Backend {
+	return &Backend{apiKey: apiKey, httpClient: &http.Client{}, maxRetries: defaultMaxRetries}
+}
+
+// SetMaxRetries overrides how many attempts a request gets on 429/5xx
+// responses before giving up. Values below 1 are treated as 1 (try once,
+// don't retry).
+func (b *Backend) SetMaxRetries(n int) {
+	if n < 1 {
+		n = 1
+	}
+	b.maxRetries = n
+}
+
+func (b *Backend) Chat(ctx context.Context, req *schema.OpenAIRequest) (*schema.OpenAIResponse, error) {
+	resp, _, err := b.ChatWithRateLimit(ctx, req)
+	return resp, err
+}
+
+// ChatWithRateLimit is like Chat but also returns the rate-limit state
+// observed on the request, so batch callers can throttle themselves. It
+// retries on 429/5xx responses, honoring Retry-After and
+// x-ratelimit-reset-* headers and falling back to exponential backoff
+// with jitter when those are absent.
+func (b *Backend) ChatWithRateLimit(ctx context.Context, req *schema.OpenAIRequest) (*schema.OpenAIResponse, backend.RateLimitInfo, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, backend.RateLimitInfo{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, body, rateLimit, err := b.doWithRetry(ctx, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.apiKey))
+		return httpReq, nil
+	}, b.maxRetries)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, rateLimit, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var openAIResp schema.OpenAIResponse
+	if err := json.Unmarshal(body, &openAIResp); err != nil {
+		return nil, rateLimit, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &openAIResp, rateLimit, nil
+}
+
+// ChatStream is like Chat but streams the response, invoking onToken with
+// each token as it arrives instead of waiting for the full completion.
+// It sets req.Stream so callers don't need to.
+func (b *Backend) ChatStream(ctx context.Context, req *schema.OpenAIRequest, onToken func(token string) error) error {
+	req.Stream = true
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.apiKey))
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk schema.StreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if err := onToken(chunk.Choices[0].Delta.Content); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (b *Backend) GenerateImage(ctx context.Context, req *schema.ImageGenerationRequest) (*schema.ImageGenerationResponse, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, body, _, err := b.doWithRetry(ctx, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/images/generations", bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.apiKey))
+		return httpReq, nil
+	}, b.maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var imgResp schema.ImageGenerationResponse
+	if err := json.Unmarshal(body, &imgResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &imgResp, nil
+}
+
+// UploadFile uploads a local file to the OpenAI files API and returns a
+// reference URL the chat completions endpoint can use in place of an
+// inline data URL, for media too large to base64-encode.
+func (b *Backend) UploadFile(ctx context.Context, filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("purpose", "vision"); err != nil {
+		return "", fmt.Errorf("failed to write purpose field: %w", err)
+	}
+
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", fmt.Errorf("failed to copy file contents: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/files", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.apiKey))
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("file upload error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var uploadResp struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &uploadResp); err != nil {
+		return "", fmt.Errorf("failed to parse upload response: %w", err)
+	}
+
+	return fmt.Sprintf("file://%s", uploadResp.ID), nil
+}
@@ -0,0 +1,144 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+)
+
+// defaultMaxRetries is how many attempts a request gets before giving up,
+// when the caller hasn't overridden it via SetMaxRetries.
+const defaultMaxRetries = 5
+
+func parseRateLimitInfo(h http.Header) backend.RateLimitInfo {
+	return backend.RateLimitInfo{
+		LimitRequests:     parseIntHeader(h, "x-ratelimit-limit-requests"),
+		RemainingRequests: parseIntHeader(h, "x-ratelimit-remaining-requests"),
+		ResetRequests:     parseDurationHeader(h, "x-ratelimit-reset-requests"),
+		LimitTokens:       parseIntHeader(h, "x-ratelimit-limit-tokens"),
+		RemainingTokens:   parseIntHeader(h, "x-ratelimit-remaining-tokens"),
+		ResetTokens:       parseDurationHeader(h, "x-ratelimit-reset-tokens"),
+	}
+}
+
+func parseIntHeader(h http.Header, key string) int {
+	v, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func parseDurationHeader(h http.Header, key string) time.Duration {
+	v := h.Get(key)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// retryAfter parses the Retry-After header, which is sent as either a
+// number of seconds or an HTTP date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// backoff returns how long to wait before attempt+1, honoring the
+// server's Retry-After / rate-limit-reset headers when present and
+// falling back to exponential backoff with jitter otherwise.
+func backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header); ok && d > 0 {
+			return d
+		}
+		if d := parseDurationHeader(resp.Header, "x-ratelimit-reset-requests"); d > 0 {
+			return d
+		}
+		if d := parseDurationHeader(resp.Header, "x-ratelimit-reset-tokens"); d > 0 {
+			return d
+		}
+	}
+
+	// Cap the exponent so the shift can't overflow time.Duration (an
+	// int64 of nanoseconds) even if a caller configures a large
+	// maxRetries.
+	const maxExponent = 10
+	exponent := attempt
+	if exponent > maxExponent {
+		exponent = maxExponent
+	}
+
+	base := 250 * time.Millisecond * time.Duration(uint(1)<<uint(exponent))
+	jitter := time.Duration(rand.Int63n(int64(base)))
+	return base + jitter
+}
+
+// isRetryableStatus reports whether status warrants a retry: 429 (rate
+// limited) or any 5xx server error.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// doWithRetry sends the request returned by newReq, retrying on 429/5xx
+// responses up to maxAttempts times. newReq is called fresh for every
+// attempt since an http.Request's body can only be read once. It honors
+// ctx cancellation during backoff sleeps.
+func (b *Backend) doWithRetry(ctx context.Context, newReq func() (*http.Request, error), maxAttempts int) (*http.Response, []byte, backend.RateLimitInfo, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var rateLimit backend.RateLimitInfo
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, nil, rateLimit, err
+		}
+
+		resp, err := b.httpClient.Do(req)
+		if err != nil {
+			return nil, nil, rateLimit, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		rateLimit = parseRateLimitInfo(resp.Header)
+
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, nil, rateLimit, fmt.Errorf("failed to read response: %w", readErr)
+		}
+
+		if !isRetryableStatus(resp.StatusCode) || attempt == maxAttempts {
+			return resp, body, rateLimit, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, rateLimit, ctx.Err()
+		case <-time.After(backoff(attempt, resp)):
+		}
+	}
+
+	return nil, nil, rateLimit, fmt.Errorf("exhausted retries")
+}
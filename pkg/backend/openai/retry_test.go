@@ -0,0 +1,164 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-ratelimit-limit-requests", "60")
+	h.Set("x-ratelimit-remaining-requests", "59")
+	h.Set("x-ratelimit-reset-requests", "1s")
+	h.Set("x-ratelimit-limit-tokens", "150000")
+	h.Set("x-ratelimit-remaining-tokens", "149000")
+	h.Set("x-ratelimit-reset-tokens", "6m0s")
+
+	got := parseRateLimitInfo(h)
+	want := backend.RateLimitInfo{
+		LimitRequests:     60,
+		RemainingRequests: 59,
+		ResetRequests:     time.Second,
+		LimitTokens:       150000,
+		RemainingTokens:   149000,
+		ResetTokens:       6 * time.Minute,
+	}
+	if got != want {
+		t.Errorf("parseRateLimitInfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "30")
+	d, ok := retryAfter(h)
+	if !ok || d != 30*time.Second {
+		t.Errorf("retryAfter(seconds) = %v, %v; want 30s, true", d, ok)
+	}
+
+	h = http.Header{}
+	future := time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat)
+	h.Set("Retry-After", future)
+	d, ok = retryAfter(h)
+	if !ok || d <= 0 || d > 2*time.Minute+time.Second {
+		t.Errorf("retryAfter(http-date) = %v, %v; want ~2m, true", d, ok)
+	}
+
+	h = http.Header{}
+	if _, ok := retryAfter(h); ok {
+		t.Errorf("retryAfter(missing header) = ok, want !ok")
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.status); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffHonorsRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "5")
+
+	if got := backoff(1, resp); got != 5*time.Second {
+		t.Errorf("backoff() = %v, want 5s", got)
+	}
+}
+
+func TestDoWithRetrySucceedsAfterRetry(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("x-ratelimit-reset-requests", "1ms")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	b := &Backend{httpClient: srv.Client()}
+	newReq := func() (*http.Request, error) { return http.NewRequest(http.MethodGet, srv.URL, nil) }
+
+	resp, body, _, err := b.doWithRetry(context.Background(), newReq, 3)
+	if err != nil {
+		t.Fatalf("doWithRetry() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("doWithRetry() status = %d, want 200", resp.StatusCode)
+	}
+	if string(body) != "ok" {
+		t.Errorf("doWithRetry() body = %q, want %q", body, "ok")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("doWithRetry() made %d request(s), want 2 (one 429 then one 200)", got)
+	}
+}
+
+func TestDoWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("x-ratelimit-reset-requests", "1ms")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	b := &Backend{httpClient: srv.Client()}
+	newReq := func() (*http.Request, error) { return http.NewRequest(http.MethodGet, srv.URL, nil) }
+
+	resp, _, _, err := b.doWithRetry(context.Background(), newReq, 3)
+	if err != nil {
+		t.Fatalf("doWithRetry() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("doWithRetry() status = %d, want 429", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("doWithRetry() made %d request(s), want 3 (it should stop retrying at maxAttempts)", got)
+	}
+}
+
+func TestDoWithRetryHonorsContextCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	b := &Backend{httpClient: srv.Client()}
+	newReq := func() (*http.Request, error) { return http.NewRequest(http.MethodGet, srv.URL, nil) }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, _, err := b.doWithRetry(ctx, newReq, 5)
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("doWithRetry() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("doWithRetry() took %v during backoff after ctx cancellation, want it to return promptly instead of waiting out the 30s Retry-After", elapsed)
+	}
+}
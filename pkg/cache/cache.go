@@ -0,0 +1,105 @@
+// Package cache is an on-disk, perceptual-hash-keyed store of prior
+// analysis results, so re-analyzing the same or near-identical images
+// (resized, re-encoded) reuses the model's earlier response instead of
+// paying for another API call.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/phash"
+)
+
+// DefaultThreshold is the maximum Hamming distance between a cached hash
+// and a queried hash for the two images to be considered the same.
+const DefaultThreshold = 5
+
+// Entry is a single cached analysis result.
+type Entry struct {
+	Prompt    string    `json:"prompt"`
+	Model     string    `json:"model"`
+	Response  string    `json:"response"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Cache maps perceptual image hashes to prior analysis results, persisted
+// as a JSON file.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[uint64]Entry
+}
+
+// DefaultDir returns $XDG_CACHE_HOME/david64-analyzer (or the platform
+// equivalent, via os.UserCacheDir).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "david64-analyzer"), nil
+}
+
+// Open loads the cache file under dir, creating dir and an empty cache if
+// neither exists yet.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		path:    filepath.Join(dir, "cache.json"),
+		entries: make(map[uint64]Entry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Lookup returns the cached entry for hash whose prompt and model match,
+// considering it a hit when the Hamming distance is within threshold.
+func (c *Cache) Lookup(hash uint64, prompt, model string, threshold int) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for storedHash, entry := range c.entries {
+		if entry.Prompt != prompt || entry.Model != model {
+			continue
+		}
+		if phash.HammingDistance(hash, storedHash) <= threshold {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Store saves entry under hash and persists the cache to disk. The lock
+// is held across the marshal and write so concurrent Store calls can't
+// race to overwrite each other's snapshot of the full cache.
+func (c *Cache) Store(hash uint64, entry Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[hash] = entry
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0o644)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAndReopenRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	entry := Entry{
+		Prompt:    "describe this",
+		Model:     "gpt-4o",
+		Response:  "a red square",
+		Timestamp: time.Now().Truncate(time.Second),
+	}
+	if err := c.Store(0x1234, entry); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("second Open() error = %v", err)
+	}
+
+	got, hit := reopened.Lookup(0x1234, entry.Prompt, entry.Model, 0)
+	if !hit {
+		t.Fatalf("Lookup() after reopen = miss, want hit")
+	}
+	if got.Response != entry.Response || !got.Timestamp.Equal(entry.Timestamp) {
+		t.Errorf("Lookup() = %+v, want %+v", got, entry)
+	}
+}
+
+func TestLookupThresholdBoundary(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	stored := Entry{Prompt: "p", Model: "m", Response: "stored"}
+	if err := c.Store(0b0000, stored); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	// 0b0111 differs from 0b0000 by 3 bits.
+	if _, hit := c.Lookup(0b0111, "p", "m", 2); hit {
+		t.Errorf("Lookup() with distance 3 and threshold 2 = hit, want miss")
+	}
+	if _, hit := c.Lookup(0b0111, "p", "m", 3); !hit {
+		t.Errorf("Lookup() with distance 3 and threshold 3 = miss, want hit")
+	}
+
+	if _, hit := c.Lookup(0b0111, "other prompt", "m", 3); hit {
+		t.Errorf("Lookup() with mismatched prompt = hit, want miss")
+	}
+	if _, hit := c.Lookup(0b0111, "p", "other model", 3); hit {
+		t.Errorf("Lookup() with mismatched model = hit, want miss")
+	}
+}
@@ -0,0 +1,539 @@
+// Package media builds chat requests for local image and video files and
+// resolves CLI-style inputs (files, directories, URLs, globs) into a flat
+// list of media to analyze.
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/cache"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/phash"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/schema"
+)
+
+const (
+	imageModel  = "gpt-4o"
+	imagePrompt = "What's in this image? Please describe it in detail."
+)
+
+// maxInlineVideoBytes is the largest video we'll inline as a base64 data
+// URL. Anything bigger is uploaded via the backend's file uploader
+// instead, since large base64 payloads blow past typical request size
+// limits.
+const maxInlineVideoBytes = 20 * 1024 * 1024
+
+var videoExtensions = map[string]string{
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+}
+
+var imageExtensions = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// supportedImageMIMETypes are the content types OpenAI's vision models
+// accept for the image_url content part.
+var supportedImageMIMETypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// isVideoFile reports whether path has a recognized video extension.
+func isVideoFile(path string) bool {
+	_, ok := videoExtensions[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// IsVideo reports whether path has a recognized video extension.
+func IsVideo(path string) bool {
+	return isVideoFile(path)
+}
+
+// isSupportedMediaFile reports whether path has a recognized image or
+// video extension.
+func isSupportedMediaFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return imageExtensions[ext] != "" || videoExtensions[ext] != ""
+}
+
+// detectImageMIMEType sniffs the MIME type of image data, falling back to
+// extension-based detection for formats http.DetectContentType may not
+// recognize (e.g. WEBP on older Go versions). It returns an error for
+// types the vision API doesn't accept, such as TIFF.
+func detectImageMIMEType(path string, data []byte) (string, error) {
+	sample := data
+	if len(sample) > 512 {
+		sample = sample[:512]
+	}
+
+	mimeType := http.DetectContentType(sample)
+	if supportedImageMIMETypes[mimeType] {
+		return mimeType, nil
+	}
+
+	if ext, ok := imageExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return ext, nil
+	}
+
+	return "", fmt.Errorf("unsupported image type %q for %s", mimeType, path)
+}
+
+// fileUploader is implemented by backends that can host large media out
+// of band (e.g. the OpenAI files API) instead of inlining it as base64.
+type fileUploader interface {
+	UploadFile(ctx context.Context, path string) (string, error)
+}
+
+// streamingChatter is implemented by backends that can stream chat
+// completions token by token.
+type streamingChatter interface {
+	ChatStream(ctx context.Context, req *schema.OpenAIRequest, onToken func(token string) error) error
+}
+
+// rateLimitChatter is implemented by backends that can report rate-limit
+// state alongside a chat completion. AnalyzeAll uses it to pace its
+// worker pool instead of firing at a fixed concurrency regardless of how
+// close the backend is to throttling.
+type rateLimitChatter interface {
+	ChatWithRateLimit(ctx context.Context, req *schema.OpenAIRequest) (*schema.OpenAIResponse, backend.RateLimitInfo, error)
+}
+
+// rateLimitLowWatermark is the remaining-requests/remaining-tokens level
+// at or below which rateLimiter.throttle starts pausing new dispatches.
+const rateLimitLowWatermark = 1
+
+// rateLimiter tracks the most recently observed RateLimitInfo across a
+// batch of requests and paces new dispatches when it shows the backend is
+// nearly out of requests or tokens. A nil *rateLimiter is a valid no-op,
+// so callers that don't have rate-limit info can pass it through freely.
+type rateLimiter struct {
+	mu   sync.Mutex
+	info backend.RateLimitInfo
+	have bool
+}
+
+func (rl *rateLimiter) record(info backend.RateLimitInfo) {
+	if rl == nil {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.info = info
+	rl.have = true
+}
+
+// throttle blocks until it's safe to dispatch another request, based on
+// the last recorded RateLimitInfo: if requests or tokens are down to
+// rateLimitLowWatermark or fewer, it waits out the reported reset window
+// (or returns early if ctx is done).
+func (rl *rateLimiter) throttle(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+
+	rl.mu.Lock()
+	info, have := rl.info, rl.have
+	rl.mu.Unlock()
+	if !have {
+		return nil
+	}
+
+	var wait time.Duration
+	if info.RemainingRequests <= rateLimitLowWatermark && info.ResetRequests > wait {
+		wait = info.ResetRequests
+	}
+	if info.RemainingTokens <= rateLimitLowWatermark && info.ResetTokens > wait {
+		wait = info.ResetTokens
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// chatWithOptionalRateLimit calls b.Chat, routing through
+// ChatWithRateLimit when both b and rl support it: it waits out any
+// throttling rl has observed before sending, then feeds the fresh
+// RateLimitInfo back into rl, so a caller pacing a batch (AnalyzeAll)
+// learns about throttling without every caller having to know about
+// rateLimitChatter. It's only called once a cache lookup has missed, so
+// cache hits never pay the throttle wait.
+func chatWithOptionalRateLimit(ctx context.Context, b backend.Backend, req *schema.OpenAIRequest, rl *rateLimiter) (*schema.OpenAIResponse, error) {
+	if rl != nil {
+		if rlc, ok := b.(rateLimitChatter); ok {
+			if err := rl.throttle(ctx); err != nil {
+				return nil, err
+			}
+			resp, info, err := rlc.ChatWithRateLimit(ctx, req)
+			if err == nil {
+				rl.record(info)
+			}
+			return resp, err
+		}
+	}
+	return b.Chat(ctx, req)
+}
+
+// AnalyzeImage sends a local image file to b alongside a text prompt and
+// returns the model's description. If c is non-nil, a perceptual-hash
+// match against a prior result is reused instead of calling b, and the
+// second return value reports whether that happened.
+func AnalyzeImage(ctx context.Context, b backend.Backend, imagePath string, c *cache.Cache) (string, bool, error) {
+	return analyzeImage(ctx, b, imagePath, c, nil)
+}
+
+// analyzeImage is AnalyzeImage's implementation, taking an optional
+// rateLimiter so AnalyzeAll can observe and pace on rate-limit state that
+// AnalyzeImage's exported signature has no room to return.
+func analyzeImage(ctx context.Context, b backend.Backend, imagePath string, c *cache.Cache, rl *rateLimiter) (string, bool, error) {
+	imageData, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType, err := detectImageMIMEType(imagePath, imageData)
+	if err != nil {
+		return "", false, err
+	}
+
+	var hash uint64
+	var hashed bool
+	if c != nil {
+		if h, err := phash.Hash(bytes.NewReader(imageData)); err == nil {
+			hash, hashed = h, true
+			if entry, hit := c.Lookup(hash, imagePrompt, imageModel, cache.DefaultThreshold); hit {
+				return entry.Response, true, nil
+			}
+		}
+	}
+
+	base64Image := base64.StdEncoding.EncodeToString(imageData)
+	dataURL := fmt.Sprintf("data:%s;base64,%s", mimeType, base64Image)
+
+	req := &schema.OpenAIRequest{
+		Model: imageModel,
+		Messages: []schema.Message{
+			{
+				Role: "user",
+				Content: []schema.ContentPart{
+					{Type: "text", Text: imagePrompt},
+					{Type: "image_url", ImageURL: &schema.ImageURL{URL: dataURL}},
+				},
+			},
+		},
+		MaxTokens: 500,
+	}
+
+	resp, err := chatWithOptionalRateLimit(ctx, b, req, rl)
+	if err != nil {
+		return "", false, err
+	}
+	if len(resp.Choices) == 0 {
+		return "", false, fmt.Errorf("no response from API")
+	}
+	description := resp.Choices[0].Message.Content
+
+	if c != nil && hashed {
+		_ = c.Store(hash, cache.Entry{
+			Prompt:    imagePrompt,
+			Model:     imageModel,
+			Response:  description,
+			Timestamp: time.Now(),
+		})
+	}
+
+	return description, false, nil
+}
+
+// AnalyzeImageStream is like AnalyzeImage but streams the description,
+// invoking onToken with each token as it arrives. b must implement
+// streamingChatter (the openai backend does).
+func AnalyzeImageStream(ctx context.Context, b backend.Backend, imagePath string, onToken func(token string) error) error {
+	streamer, ok := b.(streamingChatter)
+	if !ok {
+		return fmt.Errorf("backend does not support streaming")
+	}
+
+	imageData, err := os.ReadFile(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType, err := detectImageMIMEType(imagePath, imageData)
+	if err != nil {
+		return err
+	}
+
+	base64Image := base64.StdEncoding.EncodeToString(imageData)
+	dataURL := fmt.Sprintf("data:%s;base64,%s", mimeType, base64Image)
+
+	req := &schema.OpenAIRequest{
+		Model: imageModel,
+		Messages: []schema.Message{
+			{
+				Role: "user",
+				Content: []schema.ContentPart{
+					{Type: "text", Text: imagePrompt},
+					{Type: "image_url", ImageURL: &schema.ImageURL{URL: dataURL}},
+				},
+			},
+		},
+		MaxTokens: 500,
+	}
+
+	return streamer.ChatStream(ctx, req, onToken)
+}
+
+// AnalyzeVideo sends a local video file to b alongside a text prompt and
+// returns the model's description. Small videos are inlined as a base64
+// data URL; larger ones require b to implement fileUploader.
+func AnalyzeVideo(ctx context.Context, b backend.Backend, videoPath string) (string, error) {
+	return analyzeVideo(ctx, b, videoPath, nil)
+}
+
+// analyzeVideo is AnalyzeVideo's implementation; see analyzeImage for why
+// it takes a rateLimiter.
+func analyzeVideo(ctx context.Context, b backend.Backend, videoPath string, rl *rateLimiter) (string, error) {
+	info, err := os.Stat(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat video: %w", err)
+	}
+
+	var videoURL string
+	if info.Size() > maxInlineVideoBytes {
+		uploader, ok := b.(fileUploader)
+		if !ok {
+			return "", fmt.Errorf("video %s is %d bytes, larger than the %d byte inline limit, and the backend does not support file uploads", videoPath, info.Size(), maxInlineVideoBytes)
+		}
+		videoURL, err = uploader.UploadFile(ctx, videoPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to upload video: %w", err)
+		}
+	} else {
+		videoData, err := os.ReadFile(videoPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read video: %w", err)
+		}
+		mimeType := videoExtensions[strings.ToLower(filepath.Ext(videoPath))]
+		base64Video := base64.StdEncoding.EncodeToString(videoData)
+		videoURL = fmt.Sprintf("data:%s;base64,%s", mimeType, base64Video)
+	}
+
+	req := &schema.OpenAIRequest{
+		Model: "gpt-4o",
+		Messages: []schema.Message{
+			{
+				Role: "user",
+				Content: []schema.ContentPart{
+					{Type: "text", Text: "Describe this video in detail."},
+					{Type: "video_url", VideoURL: &schema.VideoURL{URL: videoURL}},
+				},
+			},
+		},
+		MaxTokens: 500,
+	}
+
+	resp, err := chatWithOptionalRateLimit(ctx, b, req, rl)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from API")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Analyze dispatches to AnalyzeImage or AnalyzeVideo based on the file
+// extension of mediaPath. c is only consulted for images; it may be nil
+// to disable caching.
+func Analyze(ctx context.Context, b backend.Backend, mediaPath string, c *cache.Cache) (string, bool, error) {
+	return analyze(ctx, b, mediaPath, c, nil)
+}
+
+// analyze is Analyze's implementation; see analyzeImage for why it takes
+// a rateLimiter.
+func analyze(ctx context.Context, b backend.Backend, mediaPath string, c *cache.Cache, rl *rateLimiter) (string, bool, error) {
+	if isVideoFile(mediaPath) {
+		description, err := analyzeVideo(ctx, b, mediaPath, rl)
+		return description, false, err
+	}
+	return analyzeImage(ctx, b, mediaPath, c, rl)
+}
+
+// downloadFile streams an http(s) URL to a temp file and returns its path.
+func downloadFile(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: status %d", url, resp.StatusCode)
+	}
+
+	tmpFile, err := os.CreateTemp("", "analyzer-download-*"+filepath.Ext(url))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to save download: %w", err)
+	}
+
+	return tmpFile.Name(), nil
+}
+
+// Input pairs a resolved local file path with the source label (the
+// original argument, URL, glob match, or file found by walking a
+// directory argument) it should be reported under.
+type Input struct {
+	Source string
+	Path   string
+}
+
+// ResolveInputs expands CLI arguments into concrete local files. Each
+// argument may be an http(s) URL, a directory (walked recursively for
+// supported media), a glob pattern, or a plain file path.
+//
+// URL arguments are downloaded to temp files; the returned cleanup func
+// removes exactly those temp files (never a user-provided path matched by
+// a plain argument or glob) and should be deferred by the caller once
+// analysis of the inputs is done. cleanup is non-nil even when err != nil,
+// so it's always safe to defer.
+func ResolveInputs(args []string) ([]Input, func(), error) {
+	var inputs []Input
+	var downloaded []string
+	cleanup := func() {
+		for _, path := range downloaded {
+			os.Remove(path)
+		}
+	}
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://"):
+			path, err := downloadFile(arg)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, err
+			}
+			downloaded = append(downloaded, path)
+			inputs = append(inputs, Input{Source: arg, Path: path})
+
+		default:
+			info, err := os.Stat(arg)
+			if err == nil && info.IsDir() {
+				walkErr := filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return err
+					}
+					if !info.IsDir() && isSupportedMediaFile(path) {
+						inputs = append(inputs, Input{Source: path, Path: path})
+					}
+					return nil
+				})
+				if walkErr != nil {
+					cleanup()
+					return nil, func() {}, fmt.Errorf("failed to walk %s: %w", arg, walkErr)
+				}
+				continue
+			}
+
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, fmt.Errorf("invalid glob pattern %q: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				inputs = append(inputs, Input{Source: arg, Path: arg})
+				continue
+			}
+			for _, match := range matches {
+				inputs = append(inputs, Input{Source: match, Path: match})
+			}
+		}
+	}
+
+	return inputs, cleanup, nil
+}
+
+// Result is the outcome of analyzing a single input, and is also the
+// shape emitted per-line when the CLI's -format=json is used.
+type Result struct {
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
+	Cached      bool   `json:"cached,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// AnalyzeAll runs Analyze over inputs using a bounded worker pool of size
+// concurrency, returning results in the same order as inputs. c may be
+// nil to disable the perceptual-hash cache. concurrency below 1 is
+// treated as 1.
+//
+// When b reports rate-limit state (it implements rateLimitChatter),
+// AnalyzeAll paces new dispatches once remaining requests or tokens run
+// low, rather than firing at a fixed concurrency regardless of how close
+// the backend is to throttling.
+func AnalyzeAll(ctx context.Context, b backend.Backend, inputs []Input, concurrency int, c *cache.Cache) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var rl *rateLimiter
+	if _, ok := b.(rateLimitChatter); ok {
+		rl = &rateLimiter{}
+	}
+
+	results := make([]Result, len(inputs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, input := range inputs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, input Input) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := Result{Source: input.Source}
+			description, cached, err := analyze(ctx, b, input.Path, c, rl)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Description = description
+				result.Cached = cached
+			}
+			results[i] = result
+		}(i, input)
+	}
+
+	wg.Wait()
+	return results
+}
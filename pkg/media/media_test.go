@@ -0,0 +1,62 @@
+package media
+
+import "testing"
+
+func TestDetectImageMIMEType(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		data     []byte
+		wantMIME string
+		wantErr  bool
+	}{
+		{
+			name:     "png",
+			path:     "photo.png",
+			data:     []byte("\x89PNG\r\n\x1a\n" + "rest of png data"),
+			wantMIME: "image/png",
+		},
+		{
+			name:     "jpeg",
+			path:     "photo.jpg",
+			data:     []byte("\xff\xd8\xff\xe0" + "rest of jpeg data"),
+			wantMIME: "image/jpeg",
+		},
+		{
+			name:     "gif",
+			path:     "photo.gif",
+			data:     []byte("GIF89a" + "rest of gif data"),
+			wantMIME: "image/gif",
+		},
+		{
+			name:     "webp via extension fallback",
+			path:     "photo.webp",
+			data:     []byte("not a real riff header but named .webp"),
+			wantMIME: "image/webp",
+		},
+		{
+			name:    "unsupported type",
+			path:    "photo.tiff",
+			data:    []byte("II*\x00" + "rest of tiff data"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, err := detectImageMIMEType(tt.path, tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("detectImageMIMEType(%q) = %q, want error", tt.path, mimeType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectImageMIMEType(%q) returned error: %v", tt.path, err)
+			}
+			if mimeType != tt.wantMIME {
+				t.Errorf("detectImageMIMEType(%q) = %q, want %q", tt.path, mimeType, tt.wantMIME)
+			}
+		})
+	}
+}
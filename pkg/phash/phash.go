@@ -0,0 +1,69 @@
+// Package phash computes perceptual image hashes used to recognize
+// near-duplicate images (resized or re-encoded copies of the same photo).
+package phash
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math/bits"
+)
+
+// size is the side length of the grayscale thumbnail used to compute the
+// hash.
+const size = 8
+
+// Hash decodes the image read from r and computes its average hash
+// (aHash): resize to 8x8 grayscale, then set bit i when pixel i is above
+// the mean pixel value.
+func Hash(r io.Reader) (uint64, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return 0, err
+	}
+	return HashImage(img), nil
+}
+
+// HashImage computes the average hash of an already-decoded image.
+func HashImage(img image.Image) uint64 {
+	gray := resizeGray(img, size, size)
+
+	sum := 0
+	for _, v := range gray {
+		sum += int(v)
+	}
+	mean := sum / len(gray)
+
+	var hash uint64
+	for i, v := range gray {
+		if int(v) > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// resizeGray downsamples img to w x h grayscale pixels using
+// nearest-neighbor sampling, which is good enough for a perceptual hash.
+func resizeGray(img image.Image, w, h int) []uint8 {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	out := make([]uint8, 0, w*h)
+	for y := 0; y < h; y++ {
+		sy := bounds.Min.Y + y*srcH/h
+		for x := 0; x < w; x++ {
+			sx := bounds.Min.X + x*srcW/w
+			r, g, b, _ := img.At(sx, sy).RGBA()
+			gray := uint8((r*299 + g*587 + b*114) / 1000 >> 8)
+			out = append(out, gray)
+		}
+	}
+	return out
+}
+
+// HammingDistance returns the number of bits that differ between a and b.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,48 @@
+package phash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0x00, 0xFF, 8},
+		{0b1010, 0b0101, 4},
+	}
+
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(%b, %b) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHashImageStableAndSensitive(t *testing.T) {
+	checker := image.NewGray(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			if (x/4+y/4)%2 == 0 {
+				checker.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	solid := image.NewGray(image.Rect(0, 0, 16, 16))
+
+	h1 := HashImage(checker)
+	h2 := HashImage(checker)
+	if h1 != h2 {
+		t.Fatalf("HashImage is not stable: %x != %x", h1, h2)
+	}
+
+	h3 := HashImage(solid)
+	if HammingDistance(h1, h3) == 0 {
+		t.Errorf("expected distinct images to produce different hashes, got identical %x", h1)
+	}
+}
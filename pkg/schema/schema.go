@@ -0,0 +1,67 @@
+// Package schema holds the OpenAI-compatible request and response types
+// shared by the CLI client and the server backends.
+package schema
+
+// OpenAIRequest is a chat completion request, optionally carrying image or
+// video content parts alongside text.
+type OpenAIRequest struct {
+	Model     string    `json:"model"`
+	Messages  []Message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream,omitempty"`
+}
+
+// StreamChunk is one server-sent event payload from a streaming chat
+// completion, as delivered after the chunk's "data: " prefix is stripped.
+type StreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+type Message struct {
+	Role    string        `json:"role"`
+	Content []ContentPart `json:"content"`
+}
+
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+	VideoURL *VideoURL `json:"video_url,omitempty"`
+}
+
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+type VideoURL struct {
+	URL string `json:"url"`
+}
+
+type OpenAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// ImageGenerationRequest is a request to the images/generations endpoint.
+type ImageGenerationRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	N      int    `json:"n,omitempty"`
+	Size   string `json:"size,omitempty"`
+}
+
+// ImageGenerationResponse is the response from the images/generations
+// endpoint.
+type ImageGenerationResponse struct {
+	Data []struct {
+		URL     string `json:"url,omitempty"`
+		B64JSON string `json:"b64_json,omitempty"`
+	} `json:"data"`
+}
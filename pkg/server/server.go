@@ -0,0 +1,94 @@
+// Package server exposes a LocalAI/OpenAI-compatible HTTP API backed by a
+// pluggable backend.Registry.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/DTerian64/David64_Award_Nominations/pkg/backend"
+	"github.com/DTerian64/David64_Award_Nominations/pkg/schema"
+)
+
+// defaultBackendName is the registry key every request is routed to;
+// requests have no way to select a different backend.
+const defaultBackendName = "openai"
+
+// Server routes OpenAI-compatible HTTP requests to a registered backend.
+type Server struct {
+	backends *backend.Registry
+}
+
+// New returns a Server that dispatches to the given backend registry.
+func New(backends *backend.Registry) *Server {
+	return &Server{backends: backends}
+}
+
+// Handler returns the http.Handler exposing the OpenAI-compatible routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleChat)
+	mux.HandleFunc("/v1/images/generations", s.handleImageGeneration)
+	return mux
+}
+
+// ListenAndServe starts the HTTP server on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req schema.OpenAIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := s.backends.Get(defaultBackendName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := b.Chat(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handleImageGeneration(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req schema.ImageGenerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := s.backends.Get(defaultBackendName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := b.GenerateImage(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}